@@ -0,0 +1,176 @@
+package loggy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ctxFieldKey is the context.Value key type for the well-known fields
+// WithTraceID, WithSpanID and WithRequestID attach.
+type ctxFieldKey string
+
+const (
+	traceIDKey   ctxFieldKey = "trace_id"
+	spanIDKey    ctxFieldKey = "span_id"
+	requestIDKey ctxFieldKey = "request_id"
+)
+
+// WithTraceID attaches a trace_id to ctx for later extraction by LogXxxCtx.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey, id)
+}
+
+// WithSpanID attaches a span_id to ctx for later extraction by LogXxxCtx.
+func WithSpanID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, spanIDKey, id)
+}
+
+// WithRequestID attaches a request_id to ctx for later extraction by
+// LogXxxCtx.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// ContextExtractor pulls structured fields out of a context.Context, e.g.
+// an OpenTelemetry span context or an application's own request-scoped
+// values. Register one with RegisterContextExtractor.
+type ContextExtractor func(context.Context) map[string]interface{}
+
+// contextExtractors is consulted by LogXxxCtx to build a Log's Fields from
+// ctx, in registration order. Later extractors win on key conflicts.
+// Guarded by contextExtractorsMu since RegisterContextExtractor is meant
+// to be callable from middleware init paths that can run alongside live
+// logging, not just at startup.
+var (
+	contextExtractorsMu sync.RWMutex
+	contextExtractors   = []ContextExtractor{defaultContextExtractor}
+)
+
+// RegisterContextExtractor adds fn to the list of extractors consulted by
+// LogXxxCtx. Safe to call concurrently with logging.
+func RegisterContextExtractor(fn ContextExtractor) {
+	contextExtractorsMu.Lock()
+	defer contextExtractorsMu.Unlock()
+	contextExtractors = append(contextExtractors, fn)
+}
+
+// defaultContextExtractor pulls the well-known trace_id, span_id and
+// request_id fields set by WithTraceID, WithSpanID and WithRequestID.
+func defaultContextExtractor(ctx context.Context) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if v, ok := ctx.Value(traceIDKey).(string); ok {
+		fields["trace_id"] = v
+	}
+	if v, ok := ctx.Value(spanIDKey).(string); ok {
+		fields["span_id"] = v
+	}
+	if v, ok := ctx.Value(requestIDKey).(string); ok {
+		fields["request_id"] = v
+	}
+	return fields
+}
+
+// extractContextFields runs every registered ContextExtractor over ctx and
+// merges their results.
+func extractContextFields(ctx context.Context) map[string]interface{} {
+	contextExtractorsMu.RLock()
+	extractors := contextExtractors
+	contextExtractorsMu.RUnlock()
+
+	var merged map[string]interface{}
+	for _, fn := range extractors {
+		for k, v := range fn(ctx) {
+			if merged == nil {
+				merged = make(map[string]interface{})
+			}
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// mergeContextFields combines a Logger's With() context with fields
+// extracted from ctx, preferring the Logger's own fields on key conflicts.
+func mergeContextFields(loggerContext []interface{}, ctx context.Context) map[string]interface{} {
+	fields := mergeFields(loggerContext, nil)
+	extracted := extractContextFields(ctx)
+	if len(extracted) == 0 {
+		return fields
+	}
+	if fields == nil {
+		fields = make(map[string]interface{}, len(extracted))
+	}
+	for k, v := range extracted {
+		if _, exists := fields[k]; !exists {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// loggerCtxKey is the context.Value key NewContext/FromContext use to
+// carry a Logger.
+type loggerCtxKey struct{}
+
+// NewContext returns a copy of ctx carrying l, retrievable with
+// FromContext.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// FromContext returns the Logger carried by ctx, or the zero Logger (which
+// has no sinks and silently discards everything) if none was attached.
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerCtxKey{}).(Logger); ok {
+		return l
+	}
+	return Logger{}
+}
+
+// LogInfoCtx logs msg at Info level with fields extracted from ctx.
+func (l Logger) LogInfoCtx(ctx context.Context, msg string, a ...interface{}) {
+	var logMsg Log
+	logMsg.Level = Info
+	logMsg.Msg = fmt.Sprintf(msg, a...)
+	logMsg.Fields = mergeContextFields(l.context, ctx)
+	l.enqueue(logMsg)
+}
+
+// LogDebugCtx logs msg at Debug level with fields extracted from ctx.
+func (l Logger) LogDebugCtx(ctx context.Context, msg string, a ...interface{}) {
+	var logMsg Log
+	logMsg.Level = Debug
+	logMsg.Msg = fmt.Sprintf(msg, a...)
+	logMsg.Fields = mergeContextFields(l.context, ctx)
+	l.enqueue(logMsg)
+}
+
+// LogWarnCtx logs msg at Warnings level with fields extracted from ctx.
+func (l Logger) LogWarnCtx(ctx context.Context, msg string, a ...interface{}) {
+	var logMsg Log
+	logMsg.Level = Warnings
+	logMsg.Msg = fmt.Sprintf(msg, a...)
+	logMsg.Fields = mergeContextFields(l.context, ctx)
+	l.enqueue(logMsg)
+}
+
+// LogErrorCtx logs msg at Errors level with fields extracted from ctx -
+// will notify Keybase users.
+func (l Logger) LogErrorCtx(ctx context.Context, msg string, a ...interface{}) {
+	var logMsg Log
+	logMsg.Level = Errors
+	logMsg.Msg = fmt.Sprintf(msg, a...)
+	logMsg.Fields = mergeContextFields(l.context, ctx)
+	l.enqueue(logMsg)
+}
+
+// LogCriticalCtx logs msg at Critical level with fields extracted from ctx
+// - will notify Keybase users.
+func (l Logger) LogCriticalCtx(ctx context.Context, msg string, a ...interface{}) {
+	var logMsg Log
+	logMsg.Level = Critical
+	logMsg.Msg = fmt.Sprintf(msg, a...)
+	logMsg.Fields = mergeContextFields(l.context, ctx)
+	l.enqueue(logMsg)
+}
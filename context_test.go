@@ -0,0 +1,35 @@
+package loggy
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// Concurrent RegisterContextExtractor calls racing with LogXxxCtx must not
+// trip the race detector - this is meant to be safe from HTTP/gRPC
+// middleware init paths running alongside live traffic.
+func TestRegisterContextExtractorConcurrentSafe(t *testing.T) {
+	mem := NewMemorySink(Debug)
+	l := NewLoggerWithSinks(mem)
+	defer l.Close()
+
+	ctx := WithRequestID(context.Background(), "req-1")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			RegisterContextExtractor(func(context.Context) map[string]interface{} {
+				return map[string]interface{}{"n": i}
+			})
+		}(i)
+		go func() {
+			defer wg.Done()
+			l.LogInfoCtx(ctx, "concurrent log")
+		}()
+	}
+	wg.Wait()
+	l.Flush()
+}
@@ -0,0 +1,238 @@
+package loggy
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// FilterOption configures a Filter constructed with NewFilter.
+type FilterOption func(*Filter)
+
+// FilterLevel drops any log more verbose than level before it reaches the
+// wrapped Logger. StdoutOnly logs are never dropped by this rule.
+func FilterLevel(level LogLevel) FilterOption {
+	return func(f *Filter) { f.level = &level }
+}
+
+// FilterKey drops any log whose Fields contain one of keys.
+func FilterKey(keys ...string) FilterOption {
+	return func(f *Filter) { f.dropKeys = append(f.dropKeys, keys...) }
+}
+
+// FilterValue drops any log whose Msg, or whose field values, contain one
+// of vals as a substring.
+func FilterValue(vals ...string) FilterOption {
+	return func(f *Filter) { f.dropValues = append(f.dropValues, vals...) }
+}
+
+// FilterFunc drops any log for which fn returns true.
+func FilterFunc(fn func(Log) bool) FilterOption {
+	return func(f *Filter) { f.dropFuncs = append(f.dropFuncs, fn) }
+}
+
+// RedactKeys replaces the value of any matching Fields key with "***"
+// instead of dropping the log outright, e.g. to scrub passwords or tokens
+// before they reach Keybase or a shared log file. It also scans Msg itself
+// for "key=value" and "key: value" patterns using any of keys, since most
+// existing call sites (LogError, LogCritical, ...) bake their arguments
+// into Msg via Sprintf rather than populating Fields.
+func RedactKeys(keys ...string) FilterOption {
+	return func(f *Filter) {
+		f.redactKeys = append(f.redactKeys, keys...)
+		for _, key := range keys {
+			f.redactPatterns = append(f.redactPatterns, keyValuePattern(key))
+		}
+	}
+}
+
+// keyValuePattern matches "key=value" or "key: value" (case-insensitive,
+// whitespace-tolerant) so redact() can scrub them out of free-form Msg
+// text, not just structured Fields.
+func keyValuePattern(key string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(\b` + regexp.QuoteMeta(key) + `\s*[:=]\s*)(\S+)`)
+}
+
+// Filter wraps a Logger, dropping or redacting records before they reach
+// it. Construct one with NewFilter.
+type Filter struct {
+	inner          Logger
+	level          *LogLevel
+	dropKeys       []string
+	dropValues     []string
+	dropFuncs      []func(Log) bool
+	redactKeys     []string
+	redactPatterns []*regexp.Regexp
+}
+
+// NewFilter wraps inner with the rules described by opts.
+func NewFilter(inner Logger, opts ...FilterOption) Filter {
+	f := Filter{inner: inner}
+	for _, opt := range opts {
+		opt(&f)
+	}
+	return f
+}
+
+// shouldDrop reports whether msg matches any configured drop rule.
+func (f Filter) shouldDrop(msg Log) bool {
+	if f.level != nil && msg.Level != StdoutOnly && msg.Level > *f.level {
+		return true
+	}
+	for _, key := range f.dropKeys {
+		if _, ok := msg.Fields[key]; ok {
+			return true
+		}
+	}
+	for _, val := range f.dropValues {
+		if strings.Contains(msg.Msg, val) {
+			return true
+		}
+		for _, fv := range msg.Fields {
+			if strings.Contains(fmt.Sprintf("%v", fv), val) {
+				return true
+			}
+		}
+	}
+	for _, fn := range f.dropFuncs {
+		if fn(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact returns a copy of msg with any redactKeys present in Fields
+// replaced by "***", and any "key=value"/"key: value" occurrences of those
+// keys in Msg scrubbed the same way.
+func (f Filter) redact(msg Log) Log {
+	if len(f.redactKeys) == 0 {
+		return msg
+	}
+	if len(msg.Fields) > 0 {
+		fields := make(map[string]interface{}, len(msg.Fields))
+		for k, v := range msg.Fields {
+			fields[k] = v
+		}
+		for _, key := range f.redactKeys {
+			if _, ok := fields[key]; ok {
+				fields[key] = "***"
+			}
+		}
+		msg.Fields = fields
+	}
+	for _, pattern := range f.redactPatterns {
+		msg.Msg = pattern.ReplaceAllString(msg.Msg, "${1}***")
+	}
+	return msg
+}
+
+// dispatch runs msg through the filter's rules and, if it survives, hands
+// it to the wrapped Logger.
+func (f Filter) dispatch(msg Log) {
+	if f.shouldDrop(msg) {
+		return
+	}
+	f.inner.LogMsg(f.redact(msg))
+}
+
+// LogInfo shortcut from string
+func (f Filter) LogInfo(msg string, a ...interface{}) {
+	f.dispatch(Log{Level: Info, Msg: fmt.Sprintf(msg, a...)})
+}
+
+// LogInfoKV logs msg at Info level with structured key/value fields.
+func (f Filter) LogInfoKV(msg string, keyvals ...interface{}) {
+	f.dispatch(Log{Level: Info, Msg: msg, Fields: mergeFields(f.inner.context, keyvals)})
+}
+
+// LogDebug shortcut from string
+func (f Filter) LogDebug(msg string, a ...interface{}) {
+	f.dispatch(Log{Level: Debug, Msg: fmt.Sprintf(msg, a...)})
+}
+
+// LogDebugKV logs msg at Debug level with structured key/value fields.
+func (f Filter) LogDebugKV(msg string, keyvals ...interface{}) {
+	f.dispatch(Log{Level: Debug, Msg: msg, Fields: mergeFields(f.inner.context, keyvals)})
+}
+
+// LogWarn shortcut from string
+func (f Filter) LogWarn(msg string, a ...interface{}) {
+	f.dispatch(Log{Level: Warnings, Msg: fmt.Sprintf(msg, a...)})
+}
+
+// LogWarnKV logs msg at Warnings level with structured key/value fields.
+func (f Filter) LogWarnKV(msg string, keyvals ...interface{}) {
+	f.dispatch(Log{Level: Warnings, Msg: msg, Fields: mergeFields(f.inner.context, keyvals)})
+}
+
+// LogError shortcut from string - Will notify Keybase users
+func (f Filter) LogError(msg string, a ...interface{}) {
+	f.dispatch(Log{Level: Errors, Msg: fmt.Sprintf(msg, a...)})
+}
+
+// LogErrorKV logs msg at Errors level with structured key/value fields.
+func (f Filter) LogErrorKV(msg string, keyvals ...interface{}) {
+	f.dispatch(Log{Level: Errors, Msg: msg, Fields: mergeFields(f.inner.context, keyvals)})
+}
+
+// LogCritical shortcut from string - Will notifiy Keybase users
+func (f Filter) LogCritical(msg string, a ...interface{}) {
+	f.dispatch(Log{Level: Critical, Msg: fmt.Sprintf(msg, a...)})
+}
+
+// LogCriticalKV logs msg at Critical level with structured key/value fields.
+func (f Filter) LogCriticalKV(msg string, keyvals ...interface{}) {
+	f.dispatch(Log{Level: Critical, Msg: msg, Fields: mergeFields(f.inner.context, keyvals)})
+}
+
+// LogPanic is a LogCritical shortcut that terminates program. It flushes
+// the wrapped Logger's pipeline first so the message reaches its sinks
+// before the process exits.
+func (f Filter) LogPanic(msg string, a ...interface{}) {
+	f.dispatch(Log{Level: Critical, Msg: fmt.Sprintf(msg, a...)})
+	f.inner.Flush()
+	os.Exit(-1)
+}
+
+// LogErrorType for compatibility - Will notify keybase users
+func (f Filter) LogErrorType(e error) {
+	f.dispatch(Log{Level: Critical, Msg: e.Error()})
+}
+
+// Log func, takes LogLevel and string and passes to internal handler.
+func (f Filter) Log(level LogLevel, msg string) {
+	f.dispatch(Log{Level: level, Msg: msg})
+}
+
+// LogMsg takes a type Log and passes it through the filter's rules.
+func (f Filter) LogMsg(msg Log) {
+	f.dispatch(msg)
+}
+
+// With returns a child Filter whose wrapped Logger prepends keyvals to the
+// fields of every log emitted through the LogXxxKV methods. The plain
+// LogXxx, Log and LogMsg methods do not attach them.
+func (f Filter) With(keyvals ...interface{}) Filter {
+	child := f
+	child.inner = f.inner.With(keyvals...)
+	return child
+}
+
+// Flush blocks until every Log enqueued before this call has reached its
+// sinks. Delegates to the wrapped Logger.
+func (f Filter) Flush() {
+	f.inner.Flush()
+}
+
+// Close flushes the wrapped Logger's pipeline, stops its worker goroutine,
+// and closes every sink.
+func (f Filter) Close() error {
+	return f.inner.Close()
+}
+
+// Stats reports counters about the wrapped Logger's pipeline.
+func (f Filter) Stats() Stats {
+	return f.inner.Stats()
+}
@@ -0,0 +1,67 @@
+package loggy
+
+import "testing"
+
+// RedactKeys must scrub secrets baked into Msg by Sprintf-style call sites
+// like LogError, not just structured Fields - this is the exact pattern
+// the request cited as the security gap it fixes.
+func TestRedactKeysScrubsMsg(t *testing.T) {
+	mem := NewMemorySink(Debug)
+	logger := NewLoggerWithSinks(mem)
+	filter := NewFilter(logger, RedactKeys("password"))
+	defer logger.Close()
+
+	filter.LogError("login failed: password=hunter2")
+	filter.LogError("login failed: password: hunter2")
+	logger.Flush()
+
+	logs := mem.Logs()
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 logs, got %d", len(logs))
+	}
+	for _, l := range logs {
+		if l.Msg == "login failed: password=hunter2" || l.Msg == "login failed: password: hunter2" {
+			t.Fatalf("secret leaked through unredacted: %q", l.Msg)
+		}
+	}
+}
+
+// A caller holding only a Filter - the wrap-once-use-everywhere pattern
+// RedactKeys exists for - must still be able to flush/close/inspect the
+// pipeline without separately retaining the wrapped Logger.
+func TestFilterDelegatesPipelineMethods(t *testing.T) {
+	mem := NewMemorySink(Debug)
+	logger := NewLoggerWithSinks(mem)
+	filter := NewFilter(logger, RedactKeys("password"))
+
+	filter.LogInfo("hello")
+	filter.Flush()
+
+	if logs := mem.Logs(); len(logs) != 1 {
+		t.Fatalf("expected 1 log after Flush, got %d", len(logs))
+	}
+	if stats := filter.Stats(); stats.Dropped != 0 {
+		t.Fatalf("expected no drops, got %d", stats.Dropped)
+	}
+	if err := filter.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestRedactKeysScrubsFields(t *testing.T) {
+	mem := NewMemorySink(Debug)
+	logger := NewLoggerWithSinks(mem)
+	filter := NewFilter(logger, RedactKeys("token"))
+	defer logger.Close()
+
+	filter.LogInfoKV("auth", "token", "secret-value")
+	logger.Flush()
+
+	logs := mem.Logs()
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 log, got %d", len(logs))
+	}
+	if logs[0].Fields["token"] != "***" {
+		t.Fatalf("expected token field redacted, got %v", logs[0].Fields["token"])
+	}
+}
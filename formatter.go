@@ -0,0 +1,59 @@
+package loggy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Formatter renders a Log record to the string a sink should write. Sinks
+// that write raw text (file, stdout, stderr, http) accept one through
+// SetFormatter; file, stdout and stderr default to TextFormatter and http
+// defaults to JSONFormatter.
+type Formatter interface {
+	Format(Log) (string, error)
+}
+
+// TextFormatter renders the original bracketed "[timestamp] Level: msg"
+// layout, with any Fields appended as trailing "key=value" pairs.
+type TextFormatter struct{}
+
+// Format implements Formatter.
+func (TextFormatter) Format(msg Log) (string, error) {
+	out := fmt.Sprintf("[%s] %s", timeStamp(), msg.String())
+	for _, k := range sortedFieldKeys(msg.Fields) {
+		out += fmt.Sprintf(" %s=%v", k, msg.Fields[k])
+	}
+	return out, nil
+}
+
+// JSONFormatter renders a Log as a single newline-delimited JSON object
+// with "ts", "level" and "msg" keys plus any Fields.
+type JSONFormatter struct{}
+
+// Format implements Formatter.
+func (JSONFormatter) Format(msg Log) (string, error) {
+	rec := make(map[string]interface{}, len(msg.Fields)+3)
+	for k, v := range msg.Fields {
+		rec[k] = v
+	}
+	rec["ts"] = timeStamp()
+	rec["level"] = levelNames[msg.Level]
+	rec["msg"] = msg.Msg
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal log as json: %w", err)
+	}
+	return string(b), nil
+}
+
+// sortedFieldKeys returns the keys of fields in sorted order for
+// deterministic text output.
+func sortedFieldKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
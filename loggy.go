@@ -30,6 +30,9 @@ const (
 type Log struct {
 	Level LogLevel
 	Msg   string
+	// Fields holds structured key/value context attached via Logger.With
+	// or a LogXxxKV call. May be nil when no fields were supplied.
+	Fields map[string]interface{}
 }
 
 // LogOpts to be passed to NewLogger()
@@ -52,70 +55,112 @@ type LogOpts struct {
 	ProgName string
 	// Use stdout  - Required to print to stdout
 	UseStdout bool
+	// Sinks are additional output destinations, appended to whichever
+	// sinks are derived from the fields above. Use this (or NewLoggerWithSinks)
+	// to wire up SyslogSink, HTTPSink, MemorySink, or any custom Sink.
+	Sinks []Sink
+	// BufferSize is the depth of the async pipeline's queue. Defaults to
+	// 256 when zero.
+	BufferSize int
+	// OverflowPolicy controls what happens when the pipeline's queue is
+	// full. Defaults to Block.
+	OverflowPolicy OverflowPolicy
 }
 
-// Logger with options for logging to file, keybase or stdout.
-// More functionality could be added within the internal handleLog() func.
+// Logger fans each Log out to a set of Sinks (file, keybase, stdout, ...)
+// through a single background pipeline shared with every Logger derived
+// from it via With().
 type Logger struct {
-	opts LogOpts
-	k    *keybase.Keybase
-	team keybase.Channel
+	opts    LogOpts
+	pipe    *pipeline
+	context []interface{}
 }
 
-// Generate string from type Log with severity prepended
-func (msg Log) String() string {
-	levels := [...]string{
-		"StdoutOnly",
-		"Critical",
-		"Error",
-		"Warning",
-		"Info",
-		"Debug"}
-	return fmt.Sprintf("%s: %s", levels[msg.Level], msg.Msg)
+// With returns a child Logger that prepends keyvals (alternating key, value)
+// to the fields of every log it emits through the LogXxxKV and LogXxxCtx
+// methods, in the style of go-kit/log. The plain LogXxx, Log and LogMsg
+// methods do not attach them. The parent Logger is left unmodified; both
+// share the same pipeline.
+func (l Logger) With(keyvals ...interface{}) Logger {
+	child := l
+	child.context = append(append([]interface{}{}, l.context...), keyvals...)
+	return child
 }
 
-// Generate a timestamp for non-Keybase logs
-func timeStamp() string {
-	now := time.Now()
-	return now.Format("02Jan06 15:04:05.9999")
+// enqueue hands logMsg to the Logger's pipeline. It is a no-op on the zero
+// Logger (e.g. FromContext when nothing was attached).
+func (l Logger) enqueue(logMsg Log) {
+	if l.pipe == nil {
+		return
+	}
+	l.pipe.enqueue(logMsg)
 }
 
-// Write log to file from LogOpts
-func (l Logger) toFile(msg Log) {
-	output := fmt.Sprintf("[%s] %s",
-		timeStamp(), msg.String())
-
-	f, err := os.OpenFile(l.opts.OutFile,
-		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		fmt.Println("Unable to open logging file")
+// Flush blocks until every Log enqueued before this call has reached its
+// sinks.
+func (l Logger) Flush() {
+	if l.pipe == nil {
+		return
 	}
-	defer f.Close()
-	if _, err := f.WriteString(fmt.Sprintf("%s\n", output)); err != nil {
-		fmt.Println("Error writing output to logging file")
+	l.pipe.flush()
+}
+
+// Close flushes the Logger's pipeline, stops its worker goroutine, and
+// closes every sink.
+func (l Logger) Close() error {
+	if l.pipe == nil {
+		return nil
 	}
+	return l.pipe.close()
+}
 
+// Stats reports counters about the Logger's pipeline, such as how many
+// messages have been dropped under the DropOldest/DropNewest overflow
+// policies.
+func (l Logger) Stats() Stats {
+	if l.pipe == nil {
+		return Stats{}
+	}
+	return l.pipe.stats()
 }
 
-// Send log to Keybase
-func (l Logger) toKeybase(msg Log) {
-	tag := ""
-	if msg.Level <= 2 {
-		tag = "@everyone "
+// mergeFields combines a Logger's context keyvals with per-call keyvals into
+// a Fields map. An odd trailing key is paired with "MISSING_VALUE".
+func mergeFields(context, keyvals []interface{}) map[string]interface{} {
+	all := append(append([]interface{}{}, context...), keyvals...)
+	if len(all) == 0 {
+		return nil
+	}
+	fields := make(map[string]interface{}, (len(all)+1)/2)
+	for i := 0; i < len(all); i += 2 {
+		key := fmt.Sprintf("%v", all[i])
+		if i+1 < len(all) {
+			fields[key] = all[i+1]
+		} else {
+			fields[key] = "MISSING_VALUE"
+		}
 	}
-	output := fmt.Sprintf("[%s] %s%s",
-		l.opts.ProgName, tag, msg.String())
+	return fields
+}
 
-	chat := l.k.NewChat(l.team)
-	chat.Send(output)
+// levelNames maps a LogLevel to its display name.
+var levelNames = [...]string{
+	"StdoutOnly",
+	"Critical",
+	"Error",
+	"Warning",
+	"Info",
+	"Debug"}
 
+// Generate string from type Log with severity prepended
+func (msg Log) String() string {
+	return fmt.Sprintf("%s: %s", levelNames[msg.Level], msg.Msg)
 }
 
-// Write log to Stdout
-func (l Logger) toStdout(msg Log) {
-	output := fmt.Sprintf("[%s] %s",
-		timeStamp(), msg.String())
-	fmt.Println(output)
+// Generate a timestamp for non-Keybase logs
+func timeStamp() string {
+	now := time.Now()
+	return now.Format("02Jan06 15:04:05.9999")
 }
 
 // LogInfo shortcut from string
@@ -123,7 +168,16 @@ func (l Logger) LogInfo(msg string, a ...interface{}) {
 	var logMsg Log
 	logMsg.Level = Info
 	logMsg.Msg = fmt.Sprintf(msg, a...)
-	go handleLog(l, logMsg)
+	l.enqueue(logMsg)
+}
+
+// LogInfoKV logs msg at Info level with structured key/value fields.
+func (l Logger) LogInfoKV(msg string, keyvals ...interface{}) {
+	var logMsg Log
+	logMsg.Level = Info
+	logMsg.Msg = msg
+	logMsg.Fields = mergeFields(l.context, keyvals)
+	l.enqueue(logMsg)
 }
 
 // LogDebug shortcut from string
@@ -132,7 +186,16 @@ func (l Logger) LogDebug(msg string, a ...interface{}) {
 	logMsg.Level = Debug
 	logMsg.Msg = fmt.Sprintf(msg, a...)
 
-	go handleLog(l, logMsg)
+	l.enqueue(logMsg)
+}
+
+// LogDebugKV logs msg at Debug level with structured key/value fields.
+func (l Logger) LogDebugKV(msg string, keyvals ...interface{}) {
+	var logMsg Log
+	logMsg.Level = Debug
+	logMsg.Msg = msg
+	logMsg.Fields = mergeFields(l.context, keyvals)
+	l.enqueue(logMsg)
 }
 
 // LogWarn shortcut from string
@@ -140,7 +203,16 @@ func (l Logger) LogWarn(msg string, a ...interface{}) {
 	var logMsg Log
 	logMsg.Level = Warnings
 	logMsg.Msg = fmt.Sprintf(msg, a...)
-	go handleLog(l, logMsg)
+	l.enqueue(logMsg)
+}
+
+// LogWarnKV logs msg at Warnings level with structured key/value fields.
+func (l Logger) LogWarnKV(msg string, keyvals ...interface{}) {
+	var logMsg Log
+	logMsg.Level = Warnings
+	logMsg.Msg = msg
+	logMsg.Fields = mergeFields(l.context, keyvals)
+	l.enqueue(logMsg)
 }
 
 // LogError shortcut from string - Will notify Keybase users
@@ -148,7 +220,16 @@ func (l Logger) LogError(msg string, a ...interface{}) {
 	var logMsg Log
 	logMsg.Level = Errors
 	logMsg.Msg = fmt.Sprintf(msg, a...)
-	go handleLog(l, logMsg)
+	l.enqueue(logMsg)
+}
+
+// LogErrorKV logs msg at Errors level with structured key/value fields.
+func (l Logger) LogErrorKV(msg string, keyvals ...interface{}) {
+	var logMsg Log
+	logMsg.Level = Errors
+	logMsg.Msg = msg
+	logMsg.Fields = mergeFields(l.context, keyvals)
+	l.enqueue(logMsg)
 }
 
 // LogCritical shortcut from string - Will notifiy Keybase users
@@ -156,15 +237,27 @@ func (l Logger) LogCritical(msg string, a ...interface{}) {
 	var logMsg Log
 	logMsg.Level = Critical
 	logMsg.Msg = fmt.Sprintf(msg, a...)
-	go handleLog(l, logMsg)
+	l.enqueue(logMsg)
+}
+
+// LogCriticalKV logs msg at Critical level with structured key/value fields.
+func (l Logger) LogCriticalKV(msg string, keyvals ...interface{}) {
+	var logMsg Log
+	logMsg.Level = Critical
+	logMsg.Msg = msg
+	logMsg.Fields = mergeFields(l.context, keyvals)
+	l.enqueue(logMsg)
 }
 
-// LogPanic is a LogCritical shortcut that terminates program
+// LogPanic is a LogCritical shortcut that terminates program. It flushes
+// the pipeline first so the message reaches Keybase/file sinks before the
+// process exits.
 func (l Logger) LogPanic(msg string, a ...interface{}) {
 	var logMsg Log
 	logMsg.Level = Critical
 	logMsg.Msg = fmt.Sprintf(msg, a...)
-	handleLog(l, logMsg)
+	l.enqueue(logMsg)
+	l.Flush()
 	os.Exit(-1)
 }
 
@@ -174,29 +267,7 @@ func (l Logger) LogErrorType(e error) {
 	// Will set Level to Critical without terminating program
 	logMsg.Level = Critical
 	logMsg.Msg = e.Error()
-	go handleLog(l, logMsg)
-}
-
-// Func to hack to add other logging functionality
-func handleLog(l Logger, logMsg Log) {
-
-	if logMsg.Level > l.opts.Level && logMsg.Level != 0 {
-		return
-	}
-	if logMsg.Level == 0 {
-		go l.toStdout(logMsg)
-		return
-	}
-	if l.opts.toKeybase {
-		go l.toKeybase(logMsg)
-	}
-	if l.opts.toFile {
-		go l.toFile(logMsg)
-	}
-	if l.opts.toStdout {
-		go l.toStdout(logMsg)
-	}
-
+	l.enqueue(logMsg)
 }
 
 // Log func, takes LogLevel and string and passes to internal handler.
@@ -204,12 +275,12 @@ func (l Logger) Log(level LogLevel, msg string) {
 	var logMsg Log
 	logMsg.Level = level
 	logMsg.Msg = msg
-	go handleLog(l, logMsg)
+	l.enqueue(logMsg)
 }
 
 // LogMsg takes a type Log and passes it to internal handler.
 func (l Logger) LogMsg(msg Log) {
-	go handleLog(l, msg)
+	l.enqueue(msg)
 }
 
 // PanicSafe is a deferrable function to recover from a panic operation.
@@ -219,34 +290,45 @@ func (l Logger) PanicSafe() {
 	}
 }
 
-// NewLogger creates a new logger instance using LogOpts
+// NewLogger creates a new logger instance using LogOpts. OutFile, KBTeam and
+// UseStdout are each turned into a built-in Sink at opts.Level; opts.Sinks
+// (and any sinks passed to NewLoggerWithSinks) are appended on top, so
+// callers can mix the legacy options with SyslogSink, HTTPSink, MemorySink,
+// or their own Sink implementations.
 func NewLogger(opts LogOpts) Logger {
 	if opts.Level == 0 {
 		opts.Level = 2
 	}
 	var l Logger
+	var sinks []Sink
 	if opts.KBTeam != "" {
-		l.k = keybase.NewKeybase()
-		var chann keybase.Channel
-		if opts.KBChann != "" {
-			chann.TopicName = opts.KBChann
-			chann.MembersType = keybase.TEAM
-		} else {
-			chann.MembersType = keybase.USER
-		}
-		chann.Name = opts.KBTeam
-		opts.toKeybase = true
-		if !l.k.LoggedIn {
+		k := keybase.NewKeybase()
+		if !k.LoggedIn {
 			fmt.Println("Not logged into keybase, but keybase option set.")
 			os.Exit(-1)
 		}
-		l.team = chann
+		opts.toKeybase = true
+		sinks = append(sinks, NewKeybaseSink(k, opts.KBTeam, opts.KBChann, opts.ProgName, opts.Level))
 	}
 	if opts.OutFile != "" {
 		opts.toFile = true
+		sinks = append(sinks, NewFileSink(opts.OutFile, opts.Level))
 	}
 	opts.toStdout = opts.UseStdout
+	if opts.UseStdout {
+		sinks = append(sinks, NewStdoutSink(opts.Level))
+	}
+	sinks = append(sinks, opts.Sinks...)
 	l.opts = opts
+	l.pipe = newPipeline(sinks, opts.BufferSize, opts.OverflowPolicy)
 
 	return l
 }
+
+// NewLoggerWithSinks creates a Logger backed solely by the given sinks,
+// bypassing LogOpts entirely. The pipeline uses the default buffer size
+// and blocking overflow policy; construct via NewLogger if those need
+// tuning.
+func NewLoggerWithSinks(sinks ...Sink) Logger {
+	return Logger{pipe: newPipeline(sinks, defaultBufferSize, Block)}
+}
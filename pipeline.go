@@ -0,0 +1,208 @@
+package loggy
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy controls what a Logger's pipeline does when its buffer is
+// full and a new Log arrives.
+type OverflowPolicy int
+
+const (
+	// Block waits for room in the buffer, applying backpressure to the
+	// caller. This is the default.
+	Block OverflowPolicy = iota
+	// DropOldest discards the oldest buffered Log to make room.
+	DropOldest
+	// DropNewest discards the incoming Log, leaving the buffer untouched.
+	DropNewest
+)
+
+// defaultBufferSize is the queue depth used when LogOpts.BufferSize is 0.
+const defaultBufferSize = 256
+
+// Stats reports counters about a Logger's pipeline.
+type Stats struct {
+	// Dropped is the number of Log records discarded because the buffer
+	// was full and the overflow policy was DropOldest or DropNewest.
+	Dropped uint64
+}
+
+// logEnvelope is what actually travels over a pipeline's queue: either a
+// Log to dispatch, or a flush request carrying the channel to close once
+// every envelope ahead of it has been processed.
+type logEnvelope struct {
+	msg       Log
+	flushDone chan struct{}
+}
+
+// pipeline is the single background worker shared by a Logger and every
+// Logger derived from it via With(), so all of them fan out through the
+// same bounded buffer instead of spawning a goroutine per log call.
+//
+// closeMu guards closed and serializes against enqueue/flush: both take a
+// read lock for the duration of their send on queue, so close() (which
+// takes the write lock before closing queue) can never run concurrently
+// with a send and close a channel out from under it.
+type pipeline struct {
+	sinks    []Sink
+	queue    chan logEnvelope
+	overflow OverflowPolicy
+	dropped  uint64
+	wg       sync.WaitGroup
+	once     sync.Once
+	closeMu  sync.RWMutex
+	closed   bool
+}
+
+// newPipeline starts a pipeline's worker goroutine, fanning out to sinks.
+func newPipeline(sinks []Sink, bufferSize int, overflow OverflowPolicy) *pipeline {
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	p := &pipeline{
+		sinks:    sinks,
+		queue:    make(chan logEnvelope, bufferSize),
+		overflow: overflow,
+	}
+	p.wg.Add(1)
+	go p.run()
+	return p
+}
+
+// run drains the queue until it is closed, dispatching each Log to sinks
+// and unblocking flush() calls in order.
+func (p *pipeline) run() {
+	defer p.wg.Done()
+	for env := range p.queue {
+		if env.flushDone != nil {
+			close(env.flushDone)
+			continue
+		}
+		p.dispatch(env.msg)
+	}
+}
+
+// dispatch fans msg out to every sink that accepts it. StdoutOnly messages
+// bypass per-sink levels but still only reach sinks named "stdout"; if none
+// is configured, they fall back to a plain print to the real stdout so a
+// StdoutOnly log always shows up somewhere, matching the pre-Sink behavior
+// where it printed unconditionally regardless of LogOpts.
+func (p *pipeline) dispatch(msg Log) {
+	if msg.Level == StdoutOnly {
+		wrote := false
+		for _, s := range p.sinks {
+			if s.Name() != "stdout" {
+				continue
+			}
+			if err := s.Write(msg); err != nil {
+				fmt.Printf("loggy: sink %s: %v\n", s.Name(), err)
+			}
+			wrote = true
+		}
+		if !wrote {
+			line, _ := TextFormatter{}.Format(msg)
+			fmt.Println(line)
+		}
+		return
+	}
+	for _, s := range p.sinks {
+		if msg.Level > s.Level() {
+			continue
+		}
+		if err := s.Write(msg); err != nil {
+			fmt.Printf("loggy: sink %s: %v\n", s.Name(), err)
+		}
+	}
+}
+
+// enqueue adds msg to the pipeline's buffer, applying the overflow policy
+// if it is full. A racing enqueue that loses to a concurrent close()
+// degrades to a drop instead of sending on (and panicking against) a
+// closed channel.
+func (p *pipeline) enqueue(msg Log) {
+	p.closeMu.RLock()
+	defer p.closeMu.RUnlock()
+	if p.closed {
+		atomic.AddUint64(&p.dropped, 1)
+		return
+	}
+
+	env := logEnvelope{msg: msg}
+	switch p.overflow {
+	case DropNewest:
+		select {
+		case p.queue <- env:
+		default:
+			atomic.AddUint64(&p.dropped, 1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case p.queue <- env:
+				return
+			default:
+			}
+			select {
+			case old := <-p.queue:
+				if old.flushDone != nil {
+					// A pending flush request is never discarded; requeue it
+					// so its flush() call still returns.
+					p.queue <- old
+					continue
+				}
+				atomic.AddUint64(&p.dropped, 1)
+			default:
+			}
+		}
+	default: // Block
+		p.queue <- env
+	}
+}
+
+// flush blocks until every Log enqueued before this call has been
+// dispatched to sinks. A no-op once the pipeline has been closed.
+func (p *pipeline) flush() {
+	p.closeMu.RLock()
+	defer p.closeMu.RUnlock()
+	if p.closed {
+		return
+	}
+	done := make(chan struct{})
+	p.queue <- logEnvelope{flushDone: done}
+	<-done
+}
+
+// close flushes the pipeline, stops its worker, and closes every sink. It
+// is safe to call more than once, and safe to call concurrently with
+// enqueue/flush: taking the write lock first blocks until every in-flight
+// send has completed, so queue is only ever closed once nothing else can
+// still be sending on it.
+func (p *pipeline) close() error {
+	var err error
+	p.once.Do(func() {
+		p.closeMu.Lock()
+		p.closed = true
+		p.closeMu.Unlock()
+
+		done := make(chan struct{})
+		p.queue <- logEnvelope{flushDone: done}
+		<-done
+
+		close(p.queue)
+		p.wg.Wait()
+		for _, s := range p.sinks {
+			if cerr := s.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	})
+	return err
+}
+
+// stats reports the pipeline's current counters.
+func (p *pipeline) stats() Stats {
+	return Stats{Dropped: atomic.LoadUint64(&p.dropped)}
+}
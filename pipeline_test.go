@@ -0,0 +1,37 @@
+package loggy
+
+import (
+	"sync"
+	"testing"
+)
+
+// Close() must never panic when other goroutines are still calling the
+// Logger concurrently, even a With()-derived child sharing the same
+// pipeline - a racing enqueue should degrade to a drop, not a send on a
+// closed channel.
+func TestCloseDoesNotRaceWithConcurrentLogs(t *testing.T) {
+	mem := NewMemorySink(Debug)
+	l := NewLoggerWithSinks(mem)
+	child := l.With("component", "worker")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("LogInfo panicked: %v", r)
+				}
+			}()
+			for j := 0; j < 50; j++ {
+				child.LogInfo("message %d-%d", i, j)
+			}
+		}(i)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	wg.Wait()
+}
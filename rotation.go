@@ -0,0 +1,143 @@
+package loggy
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FileSinkOptions bounds a FileSink's growth. A zero value disables
+// rotation entirely, matching NewFileSink's plain append-forever behavior.
+type FileSinkOptions struct {
+	// MaxSizeMB rotates the file once it grows past this size. 0 disables
+	// size-based rotation.
+	MaxSizeMB int
+	// MaxAgeDays deletes rotated files older than this many days. 0
+	// disables age-based cleanup.
+	MaxAgeDays int
+	// MaxBackups keeps at most this many rotated files, deleting the
+	// oldest first. 0 disables count-based cleanup.
+	MaxBackups int
+	// Compress gzips rotated files.
+	Compress bool
+	// RotateOnStart rotates any pre-existing file when the sink is
+	// constructed, so each process run starts with a fresh file.
+	RotateOnStart bool
+}
+
+// NewRotatingFileSink creates a FileSink that rotates to
+// "path.YYYYMMDD-HHMMSS" (optionally gzipped) according to opts, and sweeps
+// old backups in the background per opts.MaxAgeDays/MaxBackups.
+func NewRotatingFileSink(path string, level LogLevel, opts FileSinkOptions) *FileSink {
+	s := &FileSink{path: path, level: level, formatter: TextFormatter{}, opts: opts}
+	if opts.RotateOnStart {
+		s.mu.Lock()
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+			s.rotateLocked()
+		}
+		s.mu.Unlock()
+	}
+	if opts.MaxAgeDays > 0 || opts.MaxBackups > 0 {
+		s.stopSweep = make(chan struct{})
+		go s.sweepLoop()
+	}
+	return s
+}
+
+// rotateLocked renames the current file out of the way and, if configured,
+// compresses it, then sweeps old backups. Callers must hold s.mu.
+func (s *FileSink) rotateLocked() error {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		return nil
+	}
+	target := fmt.Sprintf("%s.%s", s.path, time.Now().Format("20060102-150405"))
+	if err := os.Rename(s.path, target); err != nil {
+		return fmt.Errorf("unable to rotate logging file: %w", err)
+	}
+	if s.opts.Compress {
+		if err := compressFile(target); err != nil {
+			return fmt.Errorf("unable to compress rotated logging file: %w", err)
+		}
+	}
+	go s.sweep()
+	return nil
+}
+
+// compressFile gzips path into path+".gz" and removes the uncompressed
+// original.
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		gz.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// sweepLoop periodically runs sweep until the sink is closed.
+func (s *FileSink) sweepLoop() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.sweep()
+		case <-s.stopSweep:
+			return
+		}
+	}
+}
+
+// sweep deletes rotated backups beyond opts.MaxBackups and older than
+// opts.MaxAgeDays, keeping the newest ones.
+func (s *FileSink) sweep() {
+	matches, err := filepath.Glob(s.path + ".*")
+	if err != nil {
+		return
+	}
+
+	type backup struct {
+		path    string
+		modTime time.Time
+	}
+	var backups []backup
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: m, modTime: info.ModTime()})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime.After(backups[j].modTime) })
+
+	now := time.Now()
+	kept := 0
+	for _, b := range backups {
+		tooOld := s.opts.MaxAgeDays > 0 && now.Sub(b.modTime) > time.Duration(s.opts.MaxAgeDays)*24*time.Hour
+		tooMany := s.opts.MaxBackups > 0 && kept >= s.opts.MaxBackups
+		if tooOld || tooMany {
+			os.Remove(b.path)
+			continue
+		}
+		kept++
+	}
+}
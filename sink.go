@@ -0,0 +1,288 @@
+package loggy
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"samhofi.us/x/keybase"
+)
+
+// Sink is an output destination for log records. A Logger fans each Log out
+// to every Sink whose Level() permits it, in place of the old hard-coded
+// toFile/toKeybase/toStdout branches.
+type Sink interface {
+	// Write delivers a single Log record to the sink.
+	Write(Log) error
+	// Name identifies the sink, e.g. for error reporting.
+	Name() string
+	// Level returns the most verbose LogLevel the sink accepts; Log.Level
+	// must be less than or equal to it to be written.
+	Level() LogLevel
+	// Close releases any resources held by the sink.
+	Close() error
+}
+
+// StdoutSink writes logs to os.Stdout.
+type StdoutSink struct {
+	level     LogLevel
+	formatter Formatter
+}
+
+// NewStdoutSink creates a Sink that writes to stdout at the given level,
+// formatted with TextFormatter by default.
+func NewStdoutSink(level LogLevel) *StdoutSink {
+	return &StdoutSink{level: level, formatter: TextFormatter{}}
+}
+
+// SetFormatter overrides the Formatter used to render records.
+func (s *StdoutSink) SetFormatter(f Formatter) { s.formatter = f }
+
+// Write prints msg to stdout.
+func (s *StdoutSink) Write(msg Log) error {
+	line, err := s.formatter.Format(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(line)
+	return err
+}
+
+// Name returns "stdout".
+func (s *StdoutSink) Name() string { return "stdout" }
+
+// Level returns the sink's minimum LogLevel.
+func (s *StdoutSink) Level() LogLevel { return s.level }
+
+// Close is a no-op for StdoutSink.
+func (s *StdoutSink) Close() error { return nil }
+
+// StderrSink writes logs to os.Stderr.
+type StderrSink struct {
+	level     LogLevel
+	formatter Formatter
+}
+
+// NewStderrSink creates a Sink that writes to stderr at the given level,
+// formatted with TextFormatter by default.
+func NewStderrSink(level LogLevel) *StderrSink {
+	return &StderrSink{level: level, formatter: TextFormatter{}}
+}
+
+// SetFormatter overrides the Formatter used to render records.
+func (s *StderrSink) SetFormatter(f Formatter) { s.formatter = f }
+
+// Write prints msg to stderr.
+func (s *StderrSink) Write(msg Log) error {
+	line, err := s.formatter.Format(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stderr, line)
+	return err
+}
+
+// Name returns "stderr".
+func (s *StderrSink) Name() string { return "stderr" }
+
+// Level returns the sink's minimum LogLevel.
+func (s *StderrSink) Level() LogLevel { return s.level }
+
+// Close is a no-op for StderrSink.
+func (s *StderrSink) Close() error { return nil }
+
+// FileSink appends logs to a file, opening it for each write. Construct
+// with NewFileSink for a plain append-forever file, or NewRotatingFileSink
+// to bound it with FileSinkOptions.
+type FileSink struct {
+	path      string
+	level     LogLevel
+	formatter Formatter
+	opts      FileSinkOptions
+	mu        sync.Mutex
+	stopSweep chan struct{}
+}
+
+// NewFileSink creates a Sink that appends logs to path at the given level,
+// formatted with TextFormatter by default. The file is never rotated; use
+// NewRotatingFileSink for long-running services.
+func NewFileSink(path string, level LogLevel) *FileSink {
+	return &FileSink{path: path, level: level, formatter: TextFormatter{}}
+}
+
+// SetFormatter overrides the Formatter used to render records.
+func (s *FileSink) SetFormatter(f Formatter) { s.formatter = f }
+
+// Write appends msg to the sink's file, creating it if necessary, rotating
+// first if the file has grown past opts.MaxSizeMB.
+func (s *FileSink) Write(msg Log) error {
+	line, err := s.formatter.Format(msg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.opts.MaxSizeMB > 0 {
+		if info, err := os.Stat(s.path); err == nil && info.Size() >= int64(s.opts.MaxSizeMB)*1024*1024 {
+			if err := s.rotateLocked(); err != nil {
+				return err
+			}
+		}
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open logging file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		return fmt.Errorf("error writing output to logging file: %w", err)
+	}
+	return nil
+}
+
+// Name returns "file".
+func (s *FileSink) Name() string { return "file" }
+
+// Level returns the sink's minimum LogLevel.
+func (s *FileSink) Level() LogLevel { return s.level }
+
+// Close stops the sink's background sweeper, if one is running.
+func (s *FileSink) Close() error {
+	if s.stopSweep != nil {
+		close(s.stopSweep)
+	}
+	return nil
+}
+
+// KeybaseSink sends logs to a Keybase chat channel.
+type KeybaseSink struct {
+	k        *keybase.Keybase
+	team     keybase.Channel
+	progName string
+	level    LogLevel
+}
+
+// NewKeybaseSink creates a Sink that sends logs to a Keybase channel.
+// progName is prepended to each message and the channel defaults to a
+// 1:1 user conversation unless chann is set, in which case it becomes a
+// team topic.
+func NewKeybaseSink(k *keybase.Keybase, kbTeam, kbChann, progName string, level LogLevel) *KeybaseSink {
+	var chann keybase.Channel
+	if kbChann != "" {
+		chann.TopicName = kbChann
+		chann.MembersType = keybase.TEAM
+	} else {
+		chann.MembersType = keybase.USER
+	}
+	chann.Name = kbTeam
+	return &KeybaseSink{k: k, team: chann, progName: progName, level: level}
+}
+
+// Write sends msg to the sink's Keybase channel, tagging @everyone for
+// Errors and Critical.
+func (s *KeybaseSink) Write(msg Log) error {
+	tag := ""
+	if msg.Level <= Errors {
+		tag = "@everyone "
+	}
+	output := fmt.Sprintf("[%s] %s%s", s.progName, tag, msg.String())
+	chat := s.k.NewChat(s.team)
+	chat.Send(output)
+	return nil
+}
+
+// Name returns "keybase".
+func (s *KeybaseSink) Name() string { return "keybase" }
+
+// Level returns the sink's minimum LogLevel.
+func (s *KeybaseSink) Level() LogLevel { return s.level }
+
+// Close is a no-op for KeybaseSink.
+func (s *KeybaseSink) Close() error { return nil }
+
+// HTTPSink POSTs each Log record as JSON to a configured URL.
+type HTTPSink struct {
+	url       string
+	level     LogLevel
+	client    *http.Client
+	formatter Formatter
+}
+
+// NewHTTPSink creates a Sink that POSTs logs to url, formatted with
+// JSONFormatter by default.
+func NewHTTPSink(url string, level LogLevel) *HTTPSink {
+	return &HTTPSink{url: url, level: level, client: &http.Client{Timeout: 10 * time.Second}, formatter: JSONFormatter{}}
+}
+
+// SetFormatter overrides the Formatter used to render the POST body.
+func (s *HTTPSink) SetFormatter(f Formatter) { s.formatter = f }
+
+// Write renders msg and POSTs it to the sink's URL.
+func (s *HTTPSink) Write(msg Log) error {
+	body, err := s.formatter.Format(msg)
+	if err != nil {
+		return fmt.Errorf("unable to format log for http sink: %w", err)
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader([]byte(body)))
+	if err != nil {
+		return fmt.Errorf("unable to post log to %s: %w", s.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("http sink received status %s from %s", resp.Status, s.url)
+	}
+	return nil
+}
+
+// Name returns "http".
+func (s *HTTPSink) Name() string { return "http" }
+
+// Level returns the sink's minimum LogLevel.
+func (s *HTTPSink) Level() LogLevel { return s.level }
+
+// Close is a no-op for HTTPSink.
+func (s *HTTPSink) Close() error { return nil }
+
+// MemorySink stores logs in memory for use in tests.
+type MemorySink struct {
+	level LogLevel
+	mu    sync.Mutex
+	logs  []Log
+}
+
+// NewMemorySink creates a Sink that records logs in memory.
+func NewMemorySink(level LogLevel) *MemorySink {
+	return &MemorySink{level: level}
+}
+
+// Write appends msg to the sink's in-memory record.
+func (s *MemorySink) Write(msg Log) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs = append(s.logs, msg)
+	return nil
+}
+
+// Name returns "memory".
+func (s *MemorySink) Name() string { return "memory" }
+
+// Level returns the sink's minimum LogLevel.
+func (s *MemorySink) Level() LogLevel { return s.level }
+
+// Close is a no-op for MemorySink.
+func (s *MemorySink) Close() error { return nil }
+
+// Logs returns a copy of the records written to the sink so far.
+func (s *MemorySink) Logs() []Log {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	logs := make([]Log, len(s.logs))
+	copy(logs, s.logs)
+	return logs
+}
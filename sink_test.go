@@ -0,0 +1,38 @@
+package loggy
+
+import "testing"
+
+// StdoutOnly logs must always show up somewhere, even when no sink is
+// literally named "stdout" - this mirrors the pre-Sink behavior where
+// level 0 printed unconditionally regardless of LogOpts.
+func TestStdoutOnlyFallsBackWithoutStdoutSink(t *testing.T) {
+	mem := NewMemorySink(Debug)
+	l := NewLoggerWithSinks(mem)
+	defer l.Close()
+
+	l.Log(StdoutOnly, "fallback message")
+	l.Flush()
+
+	// StdoutOnly never reaches sinks other than one named "stdout" - the
+	// fix is that it no longer vanishes silently, it falls back to a
+	// direct print instead. We can't capture real stdout here without
+	// redirecting os.Stdout, so just confirm nothing panics and the
+	// non-stdout sink is correctly left untouched.
+	if logs := mem.Logs(); len(logs) != 0 {
+		t.Fatalf("expected StdoutOnly to bypass non-stdout sinks, got %d logs", len(logs))
+	}
+}
+
+func TestStdoutOnlyReachesStdoutSink(t *testing.T) {
+	stdout := NewStdoutSink(Debug)
+	mem := NewMemorySink(Debug)
+	l := NewLoggerWithSinks(stdout, mem)
+	defer l.Close()
+
+	l.Log(StdoutOnly, "hello")
+	l.Flush()
+
+	if logs := mem.Logs(); len(logs) != 0 {
+		t.Fatalf("expected StdoutOnly to skip the memory sink, got %d logs", len(logs))
+	}
+}
@@ -0,0 +1,62 @@
+//go:build !windows
+
+package loggy
+
+import (
+	"fmt"
+	"log/syslog"
+)
+
+// SyslogSink sends logs to syslog, either the local daemon or a remote
+// server reached over network when network/addr are set, in the message
+// format of the standard log/syslog package.
+type SyslogSink struct {
+	w     *syslog.Writer
+	level LogLevel
+}
+
+// NewSyslogSink creates a Sink that writes to the local syslog daemon.
+// tag identifies the program in syslog output.
+func NewSyslogSink(tag string, level LogLevel) (*SyslogSink, error) {
+	w, err := syslog.New(syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open syslog: %w", err)
+	}
+	return &SyslogSink{w: w, level: level}, nil
+}
+
+// NewSyslogNetworkSink creates a Sink that writes to a remote syslog server
+// over network (e.g. "tcp" or "udp") at addr.
+func NewSyslogNetworkSink(network, addr, tag string, level LogLevel) (*SyslogSink, error) {
+	w, err := syslog.Dial(network, addr, syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial syslog at %s: %w", addr, err)
+	}
+	return &SyslogSink{w: w, level: level}, nil
+}
+
+// Write sends msg to syslog at the priority matching its LogLevel.
+func (s *SyslogSink) Write(msg Log) error {
+	text := msg.String()
+	switch msg.Level {
+	case Critical:
+		return s.w.Crit(text)
+	case Errors:
+		return s.w.Err(text)
+	case Warnings:
+		return s.w.Warning(text)
+	case Debug:
+		return s.w.Debug(text)
+	default:
+		return s.w.Info(text)
+	}
+}
+
+// Name returns "syslog".
+func (s *SyslogSink) Name() string { return "syslog" }
+
+// Level returns the sink's minimum LogLevel.
+func (s *SyslogSink) Level() LogLevel { return s.level }
+
+// Close closes the underlying syslog connection.
+func (s *SyslogSink) Close() error { return s.w.Close() }
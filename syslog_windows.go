@@ -0,0 +1,34 @@
+//go:build windows
+
+package loggy
+
+import "fmt"
+
+// SyslogSink is unavailable on Windows, which has no log/syslog support.
+type SyslogSink struct {
+	level LogLevel
+}
+
+// NewSyslogSink always returns an error on Windows.
+func NewSyslogSink(tag string, level LogLevel) (*SyslogSink, error) {
+	return nil, fmt.Errorf("loggy: syslog sink is not supported on windows")
+}
+
+// NewSyslogNetworkSink always returns an error on Windows.
+func NewSyslogNetworkSink(network, addr, tag string, level LogLevel) (*SyslogSink, error) {
+	return nil, fmt.Errorf("loggy: syslog sink is not supported on windows")
+}
+
+// Write always fails on Windows.
+func (s *SyslogSink) Write(msg Log) error {
+	return fmt.Errorf("loggy: syslog sink is not supported on windows")
+}
+
+// Name returns "syslog".
+func (s *SyslogSink) Name() string { return "syslog" }
+
+// Level returns the sink's minimum LogLevel.
+func (s *SyslogSink) Level() LogLevel { return s.level }
+
+// Close is a no-op on Windows.
+func (s *SyslogSink) Close() error { return nil }